@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapterif
+
+import (
+	"context"
+
+	"github.com/opencord/voltha-protos/v3/go/voltha"
+)
+
+// CoreProxy interface for voltha-go coreproxy.
+type CoreProxy interface {
+	UpdateCoreReference(deviceID string, coreReference string)
+	DeleteCoreReference(deviceID string)
+	GetCoreTopicForDevice(deviceID string) string
+	Close()
+	// getCoreTopic(deviceID string) kafka.Topic
+	//GetAdapterTopic(args ...string) kafka.Topic
+	// getAdapterTopic(args ...string) kafka.Topic
+	RegisterAdapter(ctx context.Context, adapter *voltha.Adapter, deviceTypes *voltha.DeviceTypes) error
+	RegisterAdapterWithEndpoint(ctx context.Context, adapter *voltha.Adapter, deviceTypes *voltha.DeviceTypes,
+		endpoint string, currentReplica int32, totalReplicas int32) error
+	GetHealthStatus(ctx context.Context) (*voltha.HealthStatus, error)
+	GetAdapter(ctx context.Context, adapterID string) (*voltha.Adapter, error)
+	ListDeviceTypes(ctx context.Context) (*voltha.DeviceTypes, error)
+	GetDeviceType(ctx context.Context, typeID string) (*voltha.DeviceType, error)
+	DeviceUpdate(ctx context.Context, device *voltha.Device) error
+	PortCreated(ctx context.Context, deviceID string, port *voltha.Port) error
+	PortsCreated(ctx context.Context, deviceID string, ports []*voltha.Port) error
+	PortsStateUpdate(ctx context.Context, deviceID string, operStatus voltha.OperStatus_Types) error
+	DeleteAllPorts(ctx context.Context, deviceID string) error
+	DeletePort(ctx context.Context, deviceID string, port *voltha.Port) error
+	DeviceStateUpdate(ctx context.Context, deviceID string,
+		connStatus voltha.ConnectStatus_Types, operStatus voltha.OperStatus_Types) error
+	DeviceStateUpdateWithReason(ctx context.Context, deviceID string,
+		connStatus voltha.ConnectStatus_Types, operStatus voltha.OperStatus_Types, reason string) error
+
+	DevicePMConfigUpdate(ctx context.Context, pmConfigs *voltha.PmConfigs) error
+	GetDevicePMConfigs(ctx context.Context, deviceID string) (*voltha.PmConfigs, error)
+	TriggerPmCollection(ctx context.Context, deviceID string) error
+	GetImageDownloadStatus(ctx context.Context, deviceID string, imageName string) (*voltha.ImageDownload, error)
+	UpdateImageDownload(ctx context.Context, deviceID string, img *voltha.ImageDownload) error
+	ChildDeviceDetected(ctx context.Context, parentDeviceID string, parentPortNo int,
+		childDeviceType string, channelID int, vendorID string, serialNumber string, onuID int64) (*voltha.Device, error)
+
+	DeviceDeleted(ctx context.Context, deviceID string) error
+	ChildDevicesLost(ctx context.Context, parentDeviceID string) error
+	ChildDevicesDetected(ctx context.Context, parentDeviceID string) error
+	ReconcileDevices(ctx context.Context, deviceIDs []string) error
+	GetDevice(ctx context.Context, parentDeviceID string, deviceID string) (*voltha.Device, error)
+	GetDevices(ctx context.Context, deviceIDs []string) (*voltha.Devices, error)
+	ListDeviceIds(ctx context.Context) (*voltha.IDs, error)
+	GetLogicalDevice(ctx context.Context, logicalDeviceID string) (*voltha.LogicalDevice, error)
+	GetLogicalDeviceId(ctx context.Context, deviceID string) (string, error)
+	GetChildDevice(ctx context.Context, parentDeviceID string, kwargs map[string]interface{}) (*voltha.Device, error)
+	GetChildDevices(ctx context.Context, parentDeviceID string) (*voltha.Devices, error)
+	GetChildDevicesPaged(ctx context.Context, parentDeviceID string, pageSize int, pageToken string) (*voltha.Devices, string, error)
+	SendPacketIn(ctx context.Context, deviceID string, port uint32, pktPayload []byte) error
+	GetDevicePort(ctx context.Context, deviceID string, portNo uint32) (*voltha.Port, error)
+	ListDevicePorts(ctx context.Context, deviceID string) (*voltha.Ports, error)
+	GetPortsByType(ctx context.Context, deviceID string, portType voltha.Port_PortType) (*voltha.Ports, error)
+	DeviceReasonUpdate(ctx context.Context, deviceID string, deviceReason string) error
+	SendDeviceError(ctx context.Context, deviceID string, errorCode int64, errorMsg string) error
+	SendKpiEvent(ctx context.Context, deviceID string, kpi *voltha.KpiEvent2) error
+	PortStateUpdate(ctx context.Context, deviceID string, pType voltha.Port_PortType, portNo uint32,
+		operStatus voltha.OperStatus_Types) error
+}
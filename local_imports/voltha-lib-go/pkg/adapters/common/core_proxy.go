@@ -0,0 +1,1813 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	a "github.com/golang/protobuf/ptypes/any"
+	"github.com/google/uuid"
+	"github.com/opencord/voltha-lib-go/v3/pkg/kafka"
+	"github.com/opencord/voltha-lib-go/v3/pkg/log"
+	ic "github.com/opencord/voltha-protos/v3/go/inter_container"
+	"github.com/opencord/voltha-protos/v3/go/voltha"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CoreProxy struct {
+	kafkaICProxy        kafka.InterContainerProxy
+	adapterTopic        string
+	coreTopic           string
+	defaultTimeout      time.Duration
+	rpcTimeouts         map[string]time.Duration
+	retryAttempts       int
+	retryBackoff        time.Duration
+	coreTopicResolver   func(deviceId string) string
+	logger              log.Logger
+	metrics             *coreProxyMetrics
+	replyTopicSuffix    string
+	replyTopicOnce      sync.Once
+	replyTopicActive    bool
+	deviceIdCoreMap     map[string]string
+	lockDeviceIdCoreMap sync.RWMutex
+}
+
+// CoreProxyOption configures optional behavior of a CoreProxy created by NewCoreProxy
+type CoreProxyOption func(*CoreProxy)
+
+// WithDefaultTimeout sets a timeout applied to every RPC that does not have a per-RPC override.
+// A zero value (the default) leaves the caller's context untouched.
+func WithDefaultTimeout(timeout time.Duration) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.defaultTimeout = timeout
+	}
+}
+
+// WithRPCTimeout overrides the default timeout for the named rpc
+func WithRPCTimeout(rpc string, timeout time.Duration) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.rpcTimeouts[rpc] = timeout
+	}
+}
+
+// WithReplyTopicSuffix directs replies to "<adapterTopic>_<suffix>" instead of the shared
+// adapter topic, e.g. with a per-instance suffix.  The proxy subscribes a consumer to the
+// dedicated topic before its first use, since the IC proxy only dispatches responses for topics
+// it consumes; if that subscription fails, replies fall back to the shared topic.  Dedicated
+// reply topics avoid head-of-line blocking on the single adapter topic across concurrent device
+// operations, at the cost of one extra Kafka topic per suffix.
+func WithReplyTopicSuffix(suffix string) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.replyTopicSuffix = suffix
+	}
+}
+
+type coreProxyMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// WithPrometheusMetrics records per-RPC request counts, error counts and a latency histogram
+// (labelled by rpc name) with the given registerer.  When the option is not supplied no metrics
+// code runs at all.
+func WithPrometheusMetrics(registerer prometheus.Registerer) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		m := &coreProxyMetrics{
+			requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "core_proxy_rpc_requests_total",
+				Help: "Number of RPCs issued to the core, per rpc name",
+			}, []string{"rpc"}),
+			errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "core_proxy_rpc_errors_total",
+				Help: "Number of core RPCs that returned a failure, per rpc name",
+			}, []string{"rpc"}),
+			latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name: "core_proxy_rpc_duration_seconds",
+				Help: "Round-trip latency of core RPCs, per rpc name",
+			}, []string{"rpc"}),
+		}
+		registerer.MustRegister(m.requests, m.errors, m.latency)
+		proxy.metrics = m
+	}
+}
+
+// WithLogger makes the proxy log through the supplied logger instead of the package one, so
+// adapter-specific fields and levels can be applied to CoreProxy logs.
+func WithLogger(l log.Logger) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.logger = l
+	}
+}
+
+// WithCoreTopicResolver registers a hook invoked when a device id has no entry in the
+// deviceIdCoreMap.  The hook should return the topic of the core owning the device, or an empty
+// string when it cannot tell; a non-empty result is cached via UpdateCoreReference.  When no hook
+// is set the proxy falls back to the generic coreTopic as before.
+func WithCoreTopicResolver(resolver func(deviceId string) string) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.coreTopicResolver = resolver
+	}
+}
+
+// WithReadRetries makes the read-only RPCs (GetDevice, GetChildDevices, ListDevicePorts, ...) retry
+// up to attempts extra times on failure, sleeping backoff between attempts.  Non-idempotent RPCs are
+// never retried.
+func WithReadRetries(attempts int, backoff time.Duration) CoreProxyOption {
+	return func(proxy *CoreProxy) {
+		proxy.retryAttempts = attempts
+		proxy.retryBackoff = backoff
+	}
+}
+
+func NewCoreProxy(kafkaProxy kafka.InterContainerProxy, adapterTopic string, coreTopic string, opts ...CoreProxyOption) *CoreProxy {
+	var proxy CoreProxy
+	proxy.kafkaICProxy = kafkaProxy
+	proxy.adapterTopic = adapterTopic
+	proxy.coreTopic = coreTopic
+	proxy.rpcTimeouts = make(map[string]time.Duration)
+	proxy.logger = logger
+	proxy.deviceIdCoreMap = make(map[string]string)
+	proxy.lockDeviceIdCoreMap = sync.RWMutex{}
+	for _, option := range opts {
+		option(&proxy)
+	}
+	proxy.logger.Debugw("TOPICS", log.Fields{"core": proxy.coreTopic, "adapter": proxy.adapterTopic})
+
+	return &proxy
+}
+
+// Close releases the proxy's resources on adapter shutdown, clearing the device-to-core reference
+// map.  It does not stop the underlying kafka proxy, which the adapter owns, and it is safe to call
+// concurrently with in-flight RPCs: those simply fall back to the generic core topic.
+func (ap *CoreProxy) Close() {
+	ap.lockDeviceIdCoreMap.Lock()
+	defer ap.lockDeviceIdCoreMap.Unlock()
+	ap.deviceIdCoreMap = make(map[string]string)
+}
+
+// invokeRPC forwards to kafkaICProxy.InvokeRPC, deriving a child context with the configured
+// per-RPC or default timeout when one is set
+func (ap *CoreProxy) invokeRPC(ctx context.Context, rpc string, toTopic *kafka.Topic, replyToTopic *kafka.Topic,
+	waitForResponse bool, key string, kvArgs ...*kafka.KVArg) (bool, *a.Any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout, ok := ap.rpcTimeouts[rpc]
+	if !ok {
+		timeout = ap.defaultTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if ap.metrics == nil {
+		return ap.invoke(ctx, rpc, toTopic, replyToTopic, waitForResponse, key, kvArgs...)
+	}
+	start := time.Now()
+	success, result := ap.invoke(ctx, rpc, toTopic, replyToTopic, waitForResponse, key, kvArgs...)
+	ap.metrics.requests.WithLabelValues(rpc).Inc()
+	if !success {
+		ap.metrics.errors.WithLabelValues(rpc).Inc()
+	}
+	ap.metrics.latency.WithLabelValues(rpc).Observe(time.Since(start).Seconds())
+	return success, result
+}
+
+// isTransientRPCFailure reports whether a failed RPC response is worth retrying.  Timeouts are
+// the mid-failover failures retries exist for; everything else is treated as definitive.  In
+// particular UNSUPPORTED_REQUEST must not be retried: it is the ic.Error zero value, and reply
+// encoders that pack handler errors without setting Code make every application error unmarshal
+// to it.  An unparseable response is still retried since well-formed errors always unpack.
+func isTransientRPCFailure(response *a.Any) bool {
+	unpackResult := &ic.Error{}
+	if err := ptypes.UnmarshalAny(response, unpackResult); err != nil {
+		return true
+	}
+	return unpackResult.Code == ic.ErrorCode_DEADLINE_EXCEEDED
+}
+
+// invoke runs the kafka RPC in its own goroutine and returns as soon as ctx expires, so a caller
+// with a short deadline is not held up by a proxy implementation that ignores cancellation.  The
+// expired case reports the same deadline-exceeded error the kafka proxy produces on timeout.
+func (ap *CoreProxy) invoke(ctx context.Context, rpc string, toTopic *kafka.Topic, replyToTopic *kafka.Topic,
+	waitForResponse bool, key string, kvArgs ...*kafka.KVArg) (bool, *a.Any) {
+	type rpcResult struct {
+		success bool
+		result  *a.Any
+	}
+	resultCh := make(chan rpcResult, 1)
+	go func() {
+		success, result := ap.kafkaICProxy.InvokeRPC(ctx, rpc, toTopic, replyToTopic, waitForResponse, key, kvArgs...)
+		resultCh <- rpcResult{success: success, result: result}
+	}()
+	select {
+	case r := <-resultCh:
+		return r.success, r.result
+	case <-ctx.Done():
+		ap.logger.Warnw("rpc-context-expired", log.Fields{"rpc": rpc, "error": ctx.Err()})
+		icErr := &ic.Error{Reason: ctx.Err().Error(), Code: ic.ErrorCode_DEADLINE_EXCEEDED}
+		result, _ := ptypes.MarshalAny(icErr)
+		return false, result
+	}
+}
+
+// invokeReadRPC issues an idempotent RPC routed on deviceId's core topic, retrying transient
+// failures up to the configured number of extra attempts.  The topic is re-resolved before each
+// retry in case the device moved to a different core while the first attempt was failing.
+func (ap *CoreProxy) invokeReadRPC(ctx context.Context, rpc string, deviceId string, replyToTopic *kafka.Topic,
+	waitForResponse bool, key string, kvArgs ...*kafka.KVArg) (bool, *a.Any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	toTopic := ap.getCoreTopic(deviceId)
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, replyToTopic, waitForResponse, key, kvArgs...)
+	for attempt := 1; !success && attempt <= ap.retryAttempts; attempt++ {
+		if ctx.Err() != nil || !isTransientRPCFailure(result) {
+			break
+		}
+		if ap.retryBackoff > 0 {
+			backoff := time.NewTimer(ap.retryBackoff)
+			select {
+			case <-ctx.Done():
+				backoff.Stop()
+				return success, result
+			case <-backoff.C:
+			}
+		}
+		toTopic = ap.getCoreTopic(deviceId)
+		ap.logger.Debugw("retrying-rpc", log.Fields{"rpc": rpc, "deviceId": deviceId, "attempt": attempt, "topic": toTopic.Name})
+		success, result = ap.invokeRPC(ctx, rpc, &toTopic, replyToTopic, waitForResponse, key, kvArgs...)
+	}
+	return success, result
+}
+
+func unPackResponse(logger log.Logger, rpc string, deviceId string, success bool, response *a.Any) error {
+	if success {
+		return nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(response, unpackResult); err != nil {
+			logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			// Don't dress an empty ic.Error up as a real failure code; make the problem explicit
+			return status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		logger.Debugw("response", log.Fields{"rpc": rpc, "deviceId": deviceId, "success": success, "error": err})
+		return status.Errorf(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), "%s: %s", rpc, unpackResult.Reason)
+	}
+}
+
+// UpdateCoreReference adds or update a core reference (really the topic name) for a given device Id
+func (ap *CoreProxy) UpdateCoreReference(deviceId string, coreReference string) {
+	ap.lockDeviceIdCoreMap.Lock()
+	defer ap.lockDeviceIdCoreMap.Unlock()
+	ap.deviceIdCoreMap[deviceId] = coreReference
+}
+
+// DeleteCoreReference removes a core reference (really the topic name) for a given device Id
+func (ap *CoreProxy) DeleteCoreReference(deviceId string) {
+	ap.lockDeviceIdCoreMap.Lock()
+	defer ap.lockDeviceIdCoreMap.Unlock()
+	delete(ap.deviceIdCoreMap, deviceId)
+}
+
+// CoreReferenceCount returns the number of device-to-core references currently held.  A count that
+// grows without bound usually means DeleteCoreReference is being missed on device deletion.
+func (ap *CoreProxy) CoreReferenceCount() int {
+	ap.lockDeviceIdCoreMap.RLock()
+	defer ap.lockDeviceIdCoreMap.RUnlock()
+	return len(ap.deviceIdCoreMap)
+}
+
+// CoreReferenceSnapshot returns a copy of the device-to-core topic map for diagnostics
+func (ap *CoreProxy) CoreReferenceSnapshot() map[string]string {
+	ap.lockDeviceIdCoreMap.RLock()
+	defer ap.lockDeviceIdCoreMap.RUnlock()
+	snapshot := make(map[string]string, len(ap.deviceIdCoreMap))
+	for deviceId, topic := range ap.deviceIdCoreMap {
+		snapshot[deviceId] = topic
+	}
+	return snapshot
+}
+
+func (ap *CoreProxy) getCoreTopic(deviceId string) kafka.Topic {
+	ap.lockDeviceIdCoreMap.RLock()
+	t, exist := ap.deviceIdCoreMap[deviceId]
+	ap.lockDeviceIdCoreMap.RUnlock()
+
+	if exist {
+		return kafka.Topic{Name: t}
+	}
+
+	// On a miss, give the resolver hook (if any) a chance to locate the owning core before
+	// falling back to the generic core topic.  The hook runs outside the lock since it may
+	// itself issue RPCs and call back into UpdateCoreReference.
+	if ap.coreTopicResolver != nil && deviceId != "" {
+		if t = ap.coreTopicResolver(deviceId); t != "" {
+			ap.UpdateCoreReference(deviceId, t)
+			return kafka.Topic{Name: t}
+		}
+	}
+
+	return kafka.Topic{Name: ap.coreTopic}
+}
+
+// GetCoreTopicForDevice returns the name of the core topic the proxy would route the given device's
+// requests to: the mapped per-core topic when one is known, the generic core topic otherwise.  It is
+// read-only and does not trigger the topic resolver hook.
+func (ap *CoreProxy) GetCoreTopicForDevice(deviceId string) string {
+	ap.lockDeviceIdCoreMap.RLock()
+	defer ap.lockDeviceIdCoreMap.RUnlock()
+	if t, exist := ap.deviceIdCoreMap[deviceId]; exist {
+		return t
+	}
+	return ap.coreTopic
+}
+
+func (ap *CoreProxy) getAdapterTopic(args ...string) kafka.Topic {
+	if ap.replyTopicSuffix == "" {
+		return kafka.Topic{Name: ap.adapterTopic}
+	}
+	// The IC proxy only dispatches responses for topics it consumes, so the dedicated reply
+	// topic must be subscribed before it is handed out.  On subscription failure keep using
+	// the shared adapter topic rather than pointing replies at an unconsumed topic.
+	ap.replyTopicOnce.Do(func() {
+		topic := kafka.Topic{Name: ap.adapterTopic + "_" + ap.replyTopicSuffix}
+		if err := ap.kafkaICProxy.SubscribeWithDefaultRequestHandler(topic, kafka.OffsetNewest); err != nil {
+			ap.logger.Warnw("reply-topic-subscription-failed", log.Fields{"topic": topic.Name, "error": err})
+			return
+		}
+		ap.replyTopicActive = true
+	})
+	if !ap.replyTopicActive {
+		return kafka.Topic{Name: ap.adapterTopic}
+	}
+	return kafka.Topic{Name: ap.adapterTopic + "_" + ap.replyTopicSuffix}
+}
+
+func (ap *CoreProxy) RegisterAdapter(ctx context.Context, adapter *voltha.Adapter, deviceTypes *voltha.DeviceTypes) error {
+	ap.logger.Debugw("registering-adapter", log.Fields{"coreTopic": ap.coreTopic, "adapterTopic": ap.adapterTopic})
+	rpc := "Register"
+	topic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+	args := make([]*kafka.KVArg, 2)
+	args[0] = &kafka.KVArg{
+		Key:   "adapter",
+		Value: adapter,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "deviceTypes",
+		Value: deviceTypes,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &topic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("Register-Adapter-response", log.Fields{"replyTopic": replyToTopic, "success": success})
+	return unPackResponse(ap.logger, rpc, "", success, result)
+}
+
+// RegisterAdapterWithEndpoint registers an adapter along with instance-specific metadata, so the
+// core can track multiple replicas of the same adapter for sharding.  The endpoint and replica
+// counts travel as extra RPC arguments since the vendored Adapter proto does not carry them.
+func (ap *CoreProxy) RegisterAdapterWithEndpoint(ctx context.Context, adapter *voltha.Adapter, deviceTypes *voltha.DeviceTypes,
+	endpoint string, currentReplica int32, totalReplicas int32) error {
+	ap.logger.Debugw("registering-adapter-with-endpoint", log.Fields{"coreTopic": ap.coreTopic, "adapterTopic": ap.adapterTopic,
+		"endpoint": endpoint, "currentReplica": currentReplica, "totalReplicas": totalReplicas})
+	rpc := "Register"
+	topic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+	args := make([]*kafka.KVArg, 5)
+	args[0] = &kafka.KVArg{
+		Key:   "adapter",
+		Value: adapter,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "deviceTypes",
+		Value: deviceTypes,
+	}
+	args[2] = &kafka.KVArg{
+		Key:   "endpoint",
+		Value: &ic.StrType{Val: endpoint},
+	}
+	args[3] = &kafka.KVArg{
+		Key:   "currentReplica",
+		Value: &ic.IntType{Val: int64(currentReplica)},
+	}
+	args[4] = &kafka.KVArg{
+		Key:   "totalReplicas",
+		Value: &ic.IntType{Val: int64(totalReplicas)},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &topic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("Register-Adapter-With-Endpoint-response", log.Fields{"replyTopic": replyToTopic, "success": success})
+	return unPackResponse(ap.logger, rpc, "", success, result)
+}
+
+// GetAdapter reads back the adapter record the core stored at registration, letting an adapter
+// confirm its RegisterAdapter took effect with the expected vendor and version.
+func (ap *CoreProxy) GetAdapter(ctx context.Context, adapterId string) (*voltha.Adapter, error) {
+	ap.logger.Debugw("GetAdapter", log.Fields{"adapterId": adapterId})
+	rpc := "GetAdapter"
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	args := []*kafka.KVArg{
+		{Key: "adapter_id", Value: &voltha.ID{Id: adapterId}},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("GetAdapter-response", log.Fields{"adapterId": adapterId, "success": success})
+
+	if success {
+		adapter := &voltha.Adapter{}
+		if err := ptypes.UnmarshalAny(result, adapter); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return adapter, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetAdapter-return", log.Fields{"adapterId": adapterId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// ListDeviceTypes returns all device types known to the core, the read-side counterpart of the
+// deviceTypes sent in RegisterAdapter.
+func (ap *CoreProxy) ListDeviceTypes(ctx context.Context) (*voltha.DeviceTypes, error) {
+	ap.logger.Debug("ListDeviceTypes")
+	rpc := "ListDeviceTypes"
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "")
+	ap.logger.Debugw("ListDeviceTypes-response", log.Fields{"success": success})
+
+	if success {
+		deviceTypes := &voltha.DeviceTypes{}
+		if err := ptypes.UnmarshalAny(result, deviceTypes); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return deviceTypes, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("ListDeviceTypes-return", log.Fields{"success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetDeviceType returns a single device type by id, so adapters can validate a type registered
+// correctly before creating devices of it.
+func (ap *CoreProxy) GetDeviceType(ctx context.Context, typeId string) (*voltha.DeviceType, error) {
+	ap.logger.Debugw("GetDeviceType", log.Fields{"typeId": typeId})
+	rpc := "GetDeviceType"
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	args := []*kafka.KVArg{
+		{Key: "device_type_id", Value: &voltha.ID{Id: typeId}},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("GetDeviceType-response", log.Fields{"typeId": typeId, "success": success})
+
+	if success {
+		deviceType := &voltha.DeviceType{}
+		if err := ptypes.UnmarshalAny(result, deviceType); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return deviceType, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetDeviceType-return", log.Fields{"typeId": typeId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetHealthStatus queries the core's health so adapters can gate heavy operations (e.g. bulk
+// reconciliation) when the core reports itself degraded.  It is not device-specific and therefore
+// routes to the general core topic.
+func (ap *CoreProxy) GetHealthStatus(ctx context.Context) (*voltha.HealthStatus, error) {
+	ap.logger.Debug("GetHealthStatus")
+	rpc := "GetHealthStatus"
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "")
+	ap.logger.Debugw("GetHealthStatus-response", log.Fields{"success": success})
+
+	if success {
+		health := &voltha.HealthStatus{}
+		if err := ptypes.UnmarshalAny(result, health); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return health, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetHealthStatus-return", log.Fields{"success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+func (ap *CoreProxy) DeviceUpdate(ctx context.Context, device *voltha.Device) error {
+	if device == nil {
+		return status.Error(codes.InvalidArgument, "device-is-nil")
+	}
+	ap.logger.Debugw("DeviceUpdate", log.Fields{"deviceId": device.Id})
+	if device.Id == "" {
+		// An empty id would route the request to the generic core topic and fail opaquely downstream
+		return status.Error(codes.InvalidArgument, "device-id-is-empty")
+	}
+	// DeviceUpdate is called in bulk-update hot paths; don't bother the core when the caller has
+	// already given up
+	if ctx != nil && ctx.Err() != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+		}
+		return status.Error(codes.Canceled, ctx.Err().Error())
+	}
+	rpc := "DeviceUpdate"
+	toTopic := ap.getCoreTopic(device.Id)
+	args := make([]*kafka.KVArg, 1)
+	args[0] = &kafka.KVArg{
+		Key:   "device",
+		Value: device,
+	}
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, device.Id, args...)
+	ap.logger.Debugw("DeviceUpdate-response", log.Fields{"deviceId": device.Id, "success": success})
+	return unPackResponse(ap.logger, rpc, device.Id, success, result)
+}
+
+func (ap *CoreProxy) PortCreated(ctx context.Context, deviceId string, port *voltha.Port) error {
+	ap.logger.Debugw("PortCreated", log.Fields{"portNo": port.PortNo})
+	rpc := "PortCreated"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "port",
+		Value: port,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("PortCreated-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// PortsCreated reports a batch of newly created ports to the core in a single RPC, avoiding one
+// round trip per port during device activation.
+func (ap *CoreProxy) PortsCreated(ctx context.Context, deviceId string, ports []*voltha.Port) error {
+	ap.logger.Debugw("PortsCreated", log.Fields{"deviceId": deviceId, "portCount": len(ports)})
+	rpc := "PortsCreated"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "ports",
+		Value: &voltha.Ports{Items: ports},
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("PortsCreated-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) PortsStateUpdate(ctx context.Context, deviceId string, operStatus voltha.OperStatus_Types) error {
+	ap.logger.Debugw("PortsStateUpdate", log.Fields{"deviceId": deviceId})
+	rpc := "PortsStateUpdate"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	oStatus := &ic.IntType{Val: int64(operStatus)}
+
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "oper_status",
+		Value: oStatus,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("PortsStateUpdate-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) DeleteAllPorts(ctx context.Context, deviceId string) error {
+	ap.logger.Debugw("DeleteAllPorts", log.Fields{"deviceId": deviceId})
+	rpc := "DeleteAllPorts"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeleteAllPorts-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// DeletePort removes a single port from a device, for adapters handling dynamic port removal
+// without recreating the whole port list.
+func (ap *CoreProxy) DeletePort(ctx context.Context, deviceId string, port *voltha.Port) error {
+	ap.logger.Debugw("DeletePort", log.Fields{"deviceId": deviceId, "portNo": port.PortNo})
+	rpc := "DeletePort"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "port",
+		Value: port,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeletePort-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) DeviceStateUpdate(ctx context.Context, deviceId string,
+	connStatus voltha.ConnectStatus_Types, operStatus voltha.OperStatus_Types) error {
+	ap.logger.Debugw("DeviceStateUpdate", log.Fields{"deviceId": deviceId})
+	rpc := "DeviceStateUpdate"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 3)
+	id := &voltha.ID{Id: deviceId}
+	oStatus := &ic.IntType{Val: int64(operStatus)}
+	cStatus := &ic.IntType{Val: int64(connStatus)}
+
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "oper_status",
+		Value: oStatus,
+	}
+	args[2] = &kafka.KVArg{
+		Key:   "connect_status",
+		Value: cStatus,
+	}
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeviceStateUpdate-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// DeviceStateUpdateWithReason updates connect status, oper status and the device reason in a single
+// RPC, avoiding the race where DeviceStateUpdate and DeviceReasonUpdate diverge between two calls.
+func (ap *CoreProxy) DeviceStateUpdateWithReason(ctx context.Context, deviceId string,
+	connStatus voltha.ConnectStatus_Types, operStatus voltha.OperStatus_Types, reason string) error {
+	ap.logger.Debugw("DeviceStateUpdateWithReason", log.Fields{"deviceId": deviceId, "reason": reason})
+	rpc := "DeviceStateUpdateWithReason"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 4)
+	id := &voltha.ID{Id: deviceId}
+	oStatus := &ic.IntType{Val: int64(operStatus)}
+	cStatus := &ic.IntType{Val: int64(connStatus)}
+	dReason := &ic.StrType{Val: reason}
+
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "oper_status",
+		Value: oStatus,
+	}
+	args[2] = &kafka.KVArg{
+		Key:   "connect_status",
+		Value: cStatus,
+	}
+	args[3] = &kafka.KVArg{
+		Key:   "device_reason",
+		Value: dReason,
+	}
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeviceStateUpdateWithReason-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// ChildDeviceArgs carries the attributes of a newly detected child device.  Extending it with new
+// optional fields does not break existing ChildDeviceDetectedWithArgs callers, unlike the long
+// positional signature of ChildDeviceDetected.
+type ChildDeviceArgs struct {
+	ParentPortNo    int
+	ChildDeviceType string
+	ChannelId       int
+	VendorId        string
+	SerialNumber    string
+	OnuId           int64
+}
+
+func (ap *CoreProxy) ChildDeviceDetected(ctx context.Context, parentDeviceId string, parentPortNo int,
+	childDeviceType string, channelId int, vendorId string, serialNumber string, onuId int64) (*voltha.Device, error) {
+	return ap.ChildDeviceDetectedWithArgs(ctx, parentDeviceId, ChildDeviceArgs{
+		ParentPortNo:    parentPortNo,
+		ChildDeviceType: childDeviceType,
+		ChannelId:       channelId,
+		VendorId:        vendorId,
+		SerialNumber:    serialNumber,
+		OnuId:           onuId,
+	})
+}
+
+func (ap *CoreProxy) ChildDeviceDetectedWithArgs(ctx context.Context, parentDeviceId string, deviceArgs ChildDeviceArgs) (*voltha.Device, error) {
+	parentPortNo := deviceArgs.ParentPortNo
+	childDeviceType := deviceArgs.ChildDeviceType
+	channelId := deviceArgs.ChannelId
+	vendorId := deviceArgs.VendorId
+	serialNumber := deviceArgs.SerialNumber
+	onuId := deviceArgs.OnuId
+	ap.logger.Debugw("ChildDeviceDetected", log.Fields{"pDeviceId": parentDeviceId, "channelId": channelId})
+
+	// Reject out-of-range identifiers here rather than letting a buggy adapter create a corrupt
+	// child device in the core.  Both values travel as unsigned 32-bit quantities on the wire.
+	if channelId < 0 || int64(channelId) > math.MaxUint32 {
+		return nil, status.Errorf(codes.InvalidArgument, "channel-id-out-of-range: %d", channelId)
+	}
+	if onuId < 0 || onuId > math.MaxUint32 {
+		return nil, status.Errorf(codes.InvalidArgument, "onu-id-out-of-range: %d", onuId)
+	}
+
+	rpc := "ChildDeviceDetected"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(parentDeviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 7)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "parent_device_id",
+		Value: id,
+	}
+	ppn := &ic.IntType{Val: int64(parentPortNo)}
+	args[1] = &kafka.KVArg{
+		Key:   "parent_port_no",
+		Value: ppn,
+	}
+	cdt := &ic.StrType{Val: childDeviceType}
+	args[2] = &kafka.KVArg{
+		Key:   "child_device_type",
+		Value: cdt,
+	}
+	channel := &ic.IntType{Val: int64(channelId)}
+	args[3] = &kafka.KVArg{
+		Key:   "channel_id",
+		Value: channel,
+	}
+	vId := &ic.StrType{Val: vendorId}
+	args[4] = &kafka.KVArg{
+		Key:   "vendor_id",
+		Value: vId,
+	}
+	sNo := &ic.StrType{Val: serialNumber}
+	args[5] = &kafka.KVArg{
+		Key:   "serial_number",
+		Value: sNo,
+	}
+	oId := &ic.IntType{Val: int64(onuId)}
+	args[6] = &kafka.KVArg{
+		Key:   "onu_id",
+		Value: oId,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("ChildDeviceDetected-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+
+	if success {
+		volthaDevice := &voltha.Device{}
+		if err := ptypes.UnmarshalAny(result, volthaDevice); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return volthaDevice, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("ChildDeviceDetected-return", log.Fields{"deviceid": parentDeviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+
+}
+
+// DeviceDeleted notifies the core that a single device has been removed, closing the lifecycle
+// started by ChildDeviceDetected.  On success the cached core reference for the device is dropped
+// so the deviceIdCoreMap does not leak entries for deleted devices.
+func (ap *CoreProxy) DeviceDeleted(ctx context.Context, deviceId string) error {
+	ap.logger.Debugw("DeviceDeleted", log.Fields{"deviceId": deviceId})
+	rpc := "DeviceDeleted"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeviceDeleted-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	// Keep the core reference on failure so a retry still routes to the owning core rather
+	// than the generic core topic
+	if success {
+		ap.DeleteCoreReference(deviceId)
+	}
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) ChildDevicesLost(ctx context.Context, parentDeviceId string) error {
+	ap.logger.Debugw("ChildDevicesLost", log.Fields{"pDeviceId": parentDeviceId})
+	rpc := "ChildDevicesLost"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(parentDeviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "parent_device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("ChildDevicesLost-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, parentDeviceId, success, result)
+}
+
+func (ap *CoreProxy) ChildDevicesDetected(ctx context.Context, parentDeviceId string) error {
+	ap.logger.Debugw("ChildDevicesDetected", log.Fields{"pDeviceId": parentDeviceId})
+	rpc := "ChildDevicesDetected"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(parentDeviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "parent_device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("ChildDevicesDetected-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, parentDeviceId, success, result)
+}
+
+func (ap *CoreProxy) GetDevice(ctx context.Context, parentDeviceId string, deviceId string) (*voltha.Device, error) {
+	ap.logger.Debugw("GetDevice", log.Fields{"deviceId": deviceId})
+	rpc := "GetDevice"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, parentDeviceId, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("GetDevice-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+
+	if success {
+		volthaDevice := &voltha.Device{}
+		if err := ptypes.UnmarshalAny(result, volthaDevice); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return volthaDevice, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetDevice-return", log.Fields{"deviceid": parentDeviceId, "success": success, "error": err})
+		// TODO:  Need to get the real error code
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetDevices fetches several devices from the core in a single RPC, saving one round trip per
+// device when an adapter reconciles many children.
+func (ap *CoreProxy) GetDevices(ctx context.Context, deviceIds []string) (*voltha.Devices, error) {
+	ap.logger.Debugw("GetDevices", log.Fields{"deviceIds": deviceIds})
+	rpc := "GetDevices"
+
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	ids := &voltha.IDs{}
+	for _, deviceId := range deviceIds {
+		ids.Items = append(ids.Items, &voltha.ID{Id: deviceId})
+	}
+	args := make([]*kafka.KVArg, 1)
+	args[0] = &kafka.KVArg{
+		Key:   "device_ids",
+		Value: ids,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("GetDevices-response", log.Fields{"success": success})
+
+	if success {
+		volthaDevices := &voltha.Devices{}
+		if err := ptypes.UnmarshalAny(result, volthaDevices); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return volthaDevices, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetDevices-return", log.Fields{"success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// ListDeviceIds asks the core which device ids it has assigned to this adapter, letting an
+// adapter reconcile its local device set against the core's view after a restart.
+func (ap *CoreProxy) ListDeviceIds(ctx context.Context) (*voltha.IDs, error) {
+	ap.logger.Debugw("ListDeviceIds", log.Fields{"coreTopic": ap.coreTopic})
+	rpc := "ListDeviceIds"
+
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "")
+	ap.logger.Debugw("ListDeviceIds-response", log.Fields{"success": success})
+
+	if success {
+		ids := &voltha.IDs{}
+		if err := ptypes.UnmarshalAny(result, ids); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return ids, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("ListDeviceIds-return", log.Fields{"success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetLogicalDevice fetches a logical device from the core, letting adapters resolve
+// logical-device context without a separate client.  Logical devices are not tied to a core via
+// deviceIdCoreMap, so this routes on the general core topic.
+func (ap *CoreProxy) GetLogicalDevice(ctx context.Context, logicalDeviceId string) (*voltha.LogicalDevice, error) {
+	ap.logger.Debugw("GetLogicalDevice", log.Fields{"logicalDeviceId": logicalDeviceId})
+	rpc := "GetLogicalDevice"
+
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	args := []*kafka.KVArg{
+		{Key: "logical_device_id", Value: &voltha.ID{Id: logicalDeviceId}},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, logicalDeviceId, args...)
+	ap.logger.Debugw("GetLogicalDevice-response", log.Fields{"logicalDeviceId": logicalDeviceId, "success": success})
+
+	if success {
+		logicalDevice := &voltha.LogicalDevice{}
+		if err := ptypes.UnmarshalAny(result, logicalDevice); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return logicalDevice, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetLogicalDevice-return", log.Fields{"logicalDeviceId": logicalDeviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetLogicalDeviceId resolves the id of the logical device a physical device belongs to
+func (ap *CoreProxy) GetLogicalDeviceId(ctx context.Context, deviceId string) (string, error) {
+	ap.logger.Debugw("GetLogicalDeviceId", log.Fields{"deviceId": deviceId})
+	rpc := "GetLogicalDeviceId"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := []*kafka.KVArg{
+		{Key: "device_id", Value: &voltha.ID{Id: deviceId}},
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, deviceId, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("GetLogicalDeviceId-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	if success {
+		logicalDeviceId := &ic.StrType{}
+		if err := ptypes.UnmarshalAny(result, logicalDeviceId); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return "", status.Error(codes.InvalidArgument, err.Error())
+		}
+		return logicalDeviceId.Val, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return "", status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetLogicalDeviceId-return", log.Fields{"deviceId": deviceId, "success": success, "error": err})
+
+		return "", status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+func (ap *CoreProxy) GetChildDevice(ctx context.Context, parentDeviceId string, kwargs map[string]interface{}) (*voltha.Device, error) {
+	ap.logger.Debugw("GetChildDevice", log.Fields{"parentDeviceId": parentDeviceId, "kwargs": kwargs})
+	rpc := "GetChildDevice"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 4)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	var cnt uint8 = 0
+	for k, v := range kwargs {
+		cnt += 1
+		if k == "serial_number" {
+			val := &ic.StrType{Val: v.(string)}
+			args[cnt] = &kafka.KVArg{
+				Key:   k,
+				Value: val,
+			}
+		} else if k == "onu_id" {
+			val := &ic.IntType{Val: int64(v.(uint32))}
+			args[cnt] = &kafka.KVArg{
+				Key:   k,
+				Value: val,
+			}
+		} else if k == "parent_port_no" {
+			val := &ic.IntType{Val: int64(v.(uint32))}
+			args[cnt] = &kafka.KVArg{
+				Key:   k,
+				Value: val,
+			}
+		}
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, parentDeviceId, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("GetChildDevice-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+
+	if success {
+		volthaDevice := &voltha.Device{}
+		if err := ptypes.UnmarshalAny(result, volthaDevice); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return volthaDevice, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetChildDevice-return", log.Fields{"deviceid": parentDeviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+func (ap *CoreProxy) GetChildDevices(ctx context.Context, parentDeviceId string) (*voltha.Devices, error) {
+	ap.logger.Debugw("GetChildDevices", log.Fields{"parentDeviceId": parentDeviceId})
+	rpc := "GetChildDevices"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, parentDeviceId, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("GetChildDevices-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+
+	if success {
+		volthaDevices := &voltha.Devices{}
+		if err := ptypes.UnmarshalAny(result, volthaDevices); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return volthaDevices, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetChildDevices-return", log.Fields{"deviceid": parentDeviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetChildDevicesPaged fetches one page of a parent's child devices, so OLTs with thousands of
+// ONUs don't pull them in a single oversized message.  The protos have no paged-devices response,
+// so the page token is a numeric offset: pass "" for the first page and the returned token for the
+// next; an empty returned token means the last page was reached.
+func (ap *CoreProxy) GetChildDevicesPaged(ctx context.Context, parentDeviceId string, pageSize int, pageToken string) (*voltha.Devices, string, error) {
+	ap.logger.Debugw("GetChildDevicesPaged", log.Fields{"parentDeviceId": parentDeviceId, "pageSize": pageSize, "pageToken": pageToken})
+	rpc := "GetChildDevicesPaged"
+
+	offset := 0
+	if pageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(pageToken); err != nil || offset < 0 {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token %q", pageToken)
+		}
+	}
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 3)
+	id := &voltha.ID{Id: parentDeviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "page_size",
+		Value: &ic.IntType{Val: int64(pageSize)},
+	}
+	args[2] = &kafka.KVArg{
+		Key:   "page_offset",
+		Value: &ic.IntType{Val: int64(offset)},
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, parentDeviceId, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("GetChildDevicesPaged-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+
+	if success {
+		volthaDevices := &voltha.Devices{}
+		if err := ptypes.UnmarshalAny(result, volthaDevices); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, "", status.Error(codes.InvalidArgument, err.Error())
+		}
+		nextPageToken := ""
+		if pageSize > 0 && len(volthaDevices.Items) == pageSize {
+			nextPageToken = strconv.Itoa(offset + pageSize)
+		}
+		return volthaDevices, nextPageToken, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, "", status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetChildDevicesPaged-return", log.Fields{"deviceid": parentDeviceId, "success": success, "error": err})
+
+		return nil, "", status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+func (ap *CoreProxy) SendPacketIn(ctx context.Context, deviceId string, port uint32, pktPayload []byte) error {
+	ap.logger.Debugw("SendPacketIn", log.Fields{"deviceId": deviceId, "port": port, "pktPayload": pktPayload})
+	rpc := "PacketIn"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 3)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	portNo := &ic.IntType{Val: int64(port)}
+	args[1] = &kafka.KVArg{
+		Key:   "port",
+		Value: portNo,
+	}
+	pkt := &ic.Packet{Payload: pktPayload}
+	args[2] = &kafka.KVArg{
+		Key:   "packet",
+		Value: pkt,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("SendPacketIn-response", log.Fields{"pDeviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) DeviceReasonUpdate(ctx context.Context, deviceId string, deviceReason string) error {
+	ap.logger.Debugw("DeviceReasonUpdate", log.Fields{"deviceId": deviceId, "deviceReason": deviceReason})
+	rpc := "DeviceReasonUpdate"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	reason := &ic.StrType{Val: deviceReason}
+	args[1] = &kafka.KVArg{
+		Key:   "device_reason",
+		Value: reason,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("DeviceReason-response", log.Fields{"pDeviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// SendDeviceError reports a structured device error (an adapter-defined code plus a message) to the
+// core, as a machine-readable complement to the free-text DeviceReasonUpdate.
+func (ap *CoreProxy) SendDeviceError(ctx context.Context, deviceId string, errorCode int64, errorMsg string) error {
+	ap.logger.Debugw("SendDeviceError", log.Fields{"deviceId": deviceId, "errorCode": errorCode, "errorMsg": errorMsg})
+	rpc := "DeviceErrorReport"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 3)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	code := &ic.IntType{Val: errorCode}
+	args[1] = &kafka.KVArg{
+		Key:   "error_code",
+		Value: code,
+	}
+	msg := &ic.StrType{Val: errorMsg}
+	args[2] = &kafka.KVArg{
+		Key:   "error_message",
+		Value: msg,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("SendDeviceError-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// SendKpiEvent ships a KPI event for a device to the core, so adapters that already hold a
+// CoreProxy don't need a separate events proxy for metrics.
+func (ap *CoreProxy) SendKpiEvent(ctx context.Context, deviceId string, kpi *voltha.KpiEvent2) error {
+	ap.logger.Debugw("SendKpiEvent", log.Fields{"deviceId": deviceId})
+	rpc := "SendKpiEvent"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "kpi_event",
+		Value: kpi,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("SendKpiEvent-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) DevicePMConfigUpdate(ctx context.Context, pmConfigs *voltha.PmConfigs) error {
+	ap.logger.Debugw("DevicePMConfigUpdate", log.Fields{"pmConfigs": pmConfigs})
+	if pmConfigs.Id == "" {
+		return status.Error(codes.InvalidArgument, "device-id-is-empty")
+	}
+	rpc := "DevicePMConfigUpdate"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(pmConfigs.Id)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	args[0] = &kafka.KVArg{
+		Key:   "device_pm_config",
+		Value: pmConfigs,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, pmConfigs.Id, args...)
+	ap.logger.Debugw("DevicePMConfigUpdate-response", log.Fields{"pDeviceId": pmConfigs.Id, "success": success})
+	return unPackResponse(ap.logger, rpc, pmConfigs.Id, success, result)
+}
+
+// TriggerPmCollection asks the core to initiate an immediate PM snapshot for a device,
+// outside the configured collection intervals.  The collected metrics are delivered through
+// the usual KPI event path rather than in the RPC response.
+func (ap *CoreProxy) TriggerPmCollection(ctx context.Context, deviceId string) error {
+	ap.logger.Debugw("TriggerPmCollection", log.Fields{"deviceId": deviceId})
+	rpc := "TriggerPmCollection"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("TriggerPmCollection-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// GetDevicePMConfigs reads the current PM configs of a device back from the core, so an adapter
+// restarting can resume metric collection at the configured intervals.
+func (ap *CoreProxy) GetDevicePMConfigs(ctx context.Context, deviceId string) (*voltha.PmConfigs, error) {
+	ap.logger.Debugw("GetDevicePMConfigs", log.Fields{"deviceId": deviceId})
+	rpc := "GetDevicePMConfigs"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, deviceId, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("GetDevicePMConfigs-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	if success {
+		pmConfigs := &voltha.PmConfigs{}
+		if err := ptypes.UnmarshalAny(result, pmConfigs); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return pmConfigs, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetDevicePMConfigs-return", log.Fields{"deviceId": deviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetImageDownloadStatus fetches the current download/activate state of a named image on a device,
+// as part of the OMCI software-upgrade flows.
+func (ap *CoreProxy) GetImageDownloadStatus(ctx context.Context, deviceId string, imageName string) (*voltha.ImageDownload, error) {
+	ap.logger.Debugw("GetImageDownloadStatus", log.Fields{"deviceId": deviceId, "imageName": imageName})
+	rpc := "GetImageDownloadStatus"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	name := &ic.StrType{Val: imageName}
+	args[1] = &kafka.KVArg{
+		Key:   "image_name",
+		Value: name,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, deviceId, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("GetImageDownloadStatus-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	if success {
+		imgDownload := &voltha.ImageDownload{}
+		if err := ptypes.UnmarshalAny(result, imgDownload); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return imgDownload, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetImageDownloadStatus-return", log.Fields{"deviceId": deviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// UpdateImageDownload reports the progress of an image download/activation back to the core
+func (ap *CoreProxy) UpdateImageDownload(ctx context.Context, deviceId string, img *voltha.ImageDownload) error {
+	ap.logger.Debugw("UpdateImageDownload", log.Fields{"deviceId": deviceId, "imageName": img.Name})
+	rpc := "UpdateImageDownload"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "image_download",
+		Value: img,
+	}
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("UpdateImageDownload-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) ReconcileChildDevices(ctx context.Context, parentDeviceId string) error {
+	ap.logger.Debugw("ReconcileChildDevices", log.Fields{"parentDeviceId": parentDeviceId})
+	rpc := "ReconcileChildDevices"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(parentDeviceId)
+	replyToTopic := ap.getAdapterTopic()
+
+	args := []*kafka.KVArg{
+		{Key: "parent_device_id", Value: &voltha.ID{Id: parentDeviceId}},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, parentDeviceId, args...)
+	ap.logger.Debugw("ReconcileChildDevices-response", log.Fields{"pDeviceId": parentDeviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, parentDeviceId, success, result)
+}
+
+// ReconcileDevices asks the core to trigger reconciliation for a batch of devices in one call,
+// typically after an adapter restart, instead of kicking each child individually.
+func (ap *CoreProxy) ReconcileDevices(ctx context.Context, deviceIds []string) error {
+	ap.logger.Debugw("ReconcileDevices", log.Fields{"deviceCount": len(deviceIds)})
+	rpc := "ReconcileDevices"
+	toTopic := kafka.Topic{Name: ap.coreTopic}
+	replyToTopic := ap.getAdapterTopic()
+
+	ids := &voltha.IDs{}
+	for _, deviceId := range deviceIds {
+		ids.Items = append(ids.Items, &voltha.ID{Id: deviceId})
+	}
+	args := []*kafka.KVArg{
+		{Key: "device_ids", Value: ids},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, "", args...)
+	ap.logger.Debugw("ReconcileDevices-response", log.Fields{"success": success})
+	return unPackResponse(ap.logger, rpc, "", success, result)
+}
+
+// SendInterAdapterMessage relays a message to another adapter through the core, for callers that
+// hold a CoreProxy but no AdapterProxy.  The message is packed into the standard inter_container
+// envelope and routed on the device-specific topic for consistency with the other device RPCs.
+func (ap *CoreProxy) SendInterAdapterMessage(ctx context.Context, deviceId string, msgType ic.InterAdapterMessageType_Types,
+	fromAdapter string, toAdapter string, msg proto.Message) error {
+	ap.logger.Debugw("SendInterAdapterMessage", log.Fields{"deviceId": deviceId, "type": msgType,
+		"from": fromAdapter, "to": toAdapter})
+	rpc := "SendInterAdapterMessage"
+
+	//Marshal the message
+	var marshalledMsg *a.Any
+	var err error
+	if marshalledMsg, err = ptypes.MarshalAny(msg); err != nil {
+		ap.logger.Warnw("cannot-marshal-msg", log.Fields{"error": err})
+		return err
+	}
+
+	//Build the inter adapter message
+	header := &ic.InterAdapterHeader{
+		Id:            uuid.New().String(),
+		Type:          msgType,
+		FromTopic:     fromAdapter,
+		ToTopic:       toAdapter,
+		ToDeviceId:    deviceId,
+		ProxyDeviceId: deviceId,
+		Timestamp:     time.Now().Unix(),
+	}
+	iaMsg := &ic.InterAdapterMessage{
+		Header: header,
+		Body:   marshalledMsg,
+	}
+
+	toTopic := ap.getCoreTopic(deviceId)
+	replyToTopic := ap.getAdapterTopic()
+	args := []*kafka.KVArg{
+		{Key: "msg", Value: iaMsg},
+	}
+
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("SendInterAdapterMessage-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+// PortStateChange describes a single port state transition for PortsStateUpdateBulk
+type PortStateChange struct {
+	PortType   voltha.Port_PortType
+	PortNo     uint32
+	OperStatus voltha.OperStatus_Types
+}
+
+// PortsStateUpdateBulk reports a batch of port state transitions to the core in a single RPC.
+// During a link flap affecting many UNIs this avoids issuing one PortStateUpdate per port.
+func (ap *CoreProxy) PortsStateUpdateBulk(ctx context.Context, deviceId string, updates []PortStateChange) error {
+	ap.logger.Debugw("PortsStateUpdateBulk", log.Fields{"deviceId": deviceId, "updateCount": len(updates)})
+	rpc := "PortsStateUpdateBulk"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+
+	// Pack the updates as a Ports message; each entry carries the port number, type and target
+	// operational status of one transition
+	ports := &voltha.Ports{}
+	for _, update := range updates {
+		ports.Items = append(ports.Items, &voltha.Port{
+			PortNo:     update.PortNo,
+			Type:       update.PortType,
+			OperStatus: update.OperStatus,
+		})
+	}
+
+	args := make([]*kafka.KVArg, 2)
+	deviceID := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: deviceID,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "ports",
+		Value: ports,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("PortsStateUpdateBulk-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) PortStateUpdate(ctx context.Context, deviceId string, pType voltha.Port_PortType, portNum uint32,
+	operStatus voltha.OperStatus_Types) error {
+	ap.logger.Debugw("PortStateUpdate", log.Fields{"deviceId": deviceId, "portType": pType, "portNo": portNum, "operation_status": operStatus})
+	rpc := "PortStateUpdate"
+	// Use a device specific topic to send the request.  The adapter handling the device creates a device
+	// specific topic
+	toTopic := ap.getCoreTopic(deviceId)
+	args := make([]*kafka.KVArg, 4)
+	deviceID := &voltha.ID{Id: deviceId}
+	portNo := &ic.IntType{Val: int64(portNum)}
+	portType := &ic.IntType{Val: int64(pType)}
+	oStatus := &ic.IntType{Val: int64(operStatus)}
+
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: deviceID,
+	}
+	args[1] = &kafka.KVArg{
+		Key:   "oper_status",
+		Value: oStatus,
+	}
+	args[2] = &kafka.KVArg{
+		Key:   "port_type",
+		Value: portType,
+	}
+	args[3] = &kafka.KVArg{
+		Key:   "port_no",
+		Value: portNo,
+	}
+
+	// Use a device specific topic as we are the only adaptercore handling requests for this device
+	replyToTopic := ap.getAdapterTopic()
+	success, result := ap.invokeRPC(ctx, rpc, &toTopic, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("PortStateUpdate-response", log.Fields{"deviceId": deviceId, "success": success})
+	return unPackResponse(ap.logger, rpc, deviceId, success, result)
+}
+
+func (ap *CoreProxy) GetDevicePort(ctx context.Context, deviceId string, portNo uint32) (*voltha.Port, error) {
+	ap.logger.Debugw("GetDevicePort", log.Fields{"deviceId": deviceId, "portNo": portNo})
+	rpc := "GetDevicePort"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 2)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+	pNo := &ic.IntType{Val: int64(portNo)}
+	args[1] = &kafka.KVArg{
+		Key:   "port_no",
+		Value: pNo,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, deviceId, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("GetDevicePort-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	if success {
+		port := &voltha.Port{}
+		if err := ptypes.UnmarshalAny(result, port); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return port, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("GetDevicePort-return", log.Fields{"deviceId": deviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+func (ap *CoreProxy) ListDevicePorts(ctx context.Context, deviceId string) (*voltha.Ports, error) {
+	ap.logger.Debugw("ListDevicePorts", log.Fields{"deviceId": deviceId})
+	rpc := "ListDevicePorts"
+
+	replyToTopic := ap.getAdapterTopic()
+
+	args := make([]*kafka.KVArg, 1)
+	id := &voltha.ID{Id: deviceId}
+	args[0] = &kafka.KVArg{
+		Key:   "device_id",
+		Value: id,
+	}
+
+	success, result := ap.invokeReadRPC(ctx, rpc, deviceId, &replyToTopic, true, deviceId, args...)
+	ap.logger.Debugw("ListDevicePorts-response", log.Fields{"deviceId": deviceId, "success": success})
+
+	if success {
+		ports := &voltha.Ports{}
+		if err := ptypes.UnmarshalAny(result, ports); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return ports, nil
+	} else {
+		unpackResult := &ic.Error{}
+		var err error
+		if err = ptypes.UnmarshalAny(result, unpackResult); err != nil {
+			ap.logger.Warnw("cannot-unmarshal-response", log.Fields{"error": err})
+			return nil, status.Errorf(codes.Internal, "%s: core returned an unparseable error response: %s", rpc, err)
+		}
+		ap.logger.Debugw("ListDevicePorts-return", log.Fields{"deviceId": deviceId, "success": success, "error": err})
+
+		return nil, status.Error(ICProxyErrorCodeToGrpcErrorCode(unpackResult.Code), unpackResult.Reason)
+	}
+}
+
+// GetPortsByType returns the ports of a device matching the given port type.  The core does not expose a
+// filtered port query, so the filtering is done here on the result of ListDevicePorts.
+func (ap *CoreProxy) GetPortsByType(ctx context.Context, deviceId string, portType voltha.Port_PortType) (*voltha.Ports, error) {
+	ap.logger.Debugw("GetPortsByType", log.Fields{"deviceId": deviceId, "portType": portType})
+
+	ports, err := ap.ListDevicePorts(ctx, deviceId)
+	if err != nil {
+		return nil, err
+	}
+	filtered := &voltha.Ports{}
+	for _, port := range ports.Items {
+		if port.Type == portType {
+			filtered.Items = append(filtered.Items, port)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,617 @@
+/*
+ * Copyright 2019-present Open Networking Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	adapterIf "github.com/opencord/voltha-lib-go/v3/pkg/adapters/adapterif"
+	"github.com/opencord/voltha-lib-go/v3/pkg/kafka"
+	"github.com/opencord/voltha-lib-go/v3/pkg/mocks"
+	ic "github.com/opencord/voltha-protos/v3/go/inter_container"
+	"github.com/opencord/voltha-protos/v3/go/voltha"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCoreProxyImplementsAdapterIfCoreProxy(t *testing.T) {
+	proxy := &CoreProxy{}
+
+	if _, ok := interface{}(proxy).(adapterIf.CoreProxy); !ok {
+		t.Error("common CoreProxy does not implement adapterif.CoreProxy interface")
+	}
+
+}
+
+func TestCoreProxy_GetChildDevice_sn(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: &voltha.Device{Id: "testDevice"},
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	kwargs := make(map[string]interface{})
+	kwargs["serial_number"] = "TEST00000000001"
+
+	parentDeviceId := "aabbcc"
+	device, error := proxy.GetChildDevice(context.TODO(), parentDeviceId, kwargs)
+
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1)
+	call := mockKafkaIcProxy.InvokeRpcSpy.Calls[1]
+	assert.Equal(t, call.Rpc, "GetChildDevice")
+	assert.Equal(t, call.ToTopic, &kafka.Topic{Name: "testCoreTopic"})
+	assert.Equal(t, call.ReplyToTopic, &kafka.Topic{Name: "testAdapterTopic"})
+	assert.Equal(t, call.WaitForResponse, true)
+	assert.Equal(t, call.Key, parentDeviceId)
+	assert.Equal(t, call.KvArgs[0], &kafka.KVArg{Key: "device_id", Value: &voltha.ID{Id: parentDeviceId}})
+	assert.Equal(t, call.KvArgs[1], &kafka.KVArg{Key: "serial_number", Value: &ic.StrType{Val: kwargs["serial_number"].(string)}})
+
+	assert.Equal(t, "testDevice", device.Id)
+	assert.Equal(t, nil, error)
+}
+
+func TestCoreProxy_GetChildDevice_id(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: &voltha.Device{Id: "testDevice"},
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	kwargs := make(map[string]interface{})
+	kwargs["onu_id"] = uint32(1234)
+
+	parentDeviceId := "aabbcc"
+	device, error := proxy.GetChildDevice(context.TODO(), parentDeviceId, kwargs)
+
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1)
+	call := mockKafkaIcProxy.InvokeRpcSpy.Calls[1]
+	assert.Equal(t, call.Rpc, "GetChildDevice")
+	assert.Equal(t, call.ToTopic, &kafka.Topic{Name: "testCoreTopic"})
+	assert.Equal(t, call.ReplyToTopic, &kafka.Topic{Name: "testAdapterTopic"})
+	assert.Equal(t, call.WaitForResponse, true)
+	assert.Equal(t, call.Key, parentDeviceId)
+	assert.Equal(t, call.KvArgs[0], &kafka.KVArg{Key: "device_id", Value: &voltha.ID{Id: parentDeviceId}})
+	assert.Equal(t, call.KvArgs[1], &kafka.KVArg{Key: "onu_id", Value: &ic.IntType{Val: int64(kwargs["onu_id"].(uint32))}})
+
+	assert.Equal(t, "testDevice", device.Id)
+	assert.Equal(t, nil, error)
+}
+
+func TestCoreProxy_GetChildDevice_fail_timeout(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:   make(map[int]mocks.InvokeRpcArgs),
+			Timeout: true,
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	kwargs := make(map[string]interface{})
+	kwargs["onu_id"] = uint32(1234)
+
+	parentDeviceId := "aabbcc"
+	device, error := proxy.GetChildDevice(context.TODO(), parentDeviceId, kwargs)
+
+	assert.Nil(t, device)
+	parsedErr, _ := status.FromError(error)
+
+	assert.Equal(t, parsedErr.Code(), codes.DeadlineExceeded)
+}
+
+func TestCoreProxy_GetChildDevice_fail_unmarhsal(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: &voltha.LogicalDevice{Id: "testDevice"},
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	kwargs := make(map[string]interface{})
+	kwargs["onu_id"] = uint32(1234)
+
+	parentDeviceId := "aabbcc"
+	device, error := proxy.GetChildDevice(context.TODO(), parentDeviceId, kwargs)
+
+	assert.Nil(t, device)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+}
+
+func TestCoreProxy_GetChildDevices_success(t *testing.T) {
+
+	devicesResponse := &voltha.Devices{}
+
+	devicesResponse.Items = append(devicesResponse.Items, &voltha.Device{Id: "testDevice1"})
+	devicesResponse.Items = append(devicesResponse.Items, &voltha.Device{Id: "testDevice2"})
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: devicesResponse,
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	parentDeviceId := "aabbcc"
+	devices, error := proxy.GetChildDevices(context.TODO(), parentDeviceId)
+
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1)
+	call := mockKafkaIcProxy.InvokeRpcSpy.Calls[1]
+	assert.Equal(t, call.Rpc, "GetChildDevices")
+	assert.Equal(t, call.ToTopic, &kafka.Topic{Name: "testCoreTopic"})
+	assert.Equal(t, call.ReplyToTopic, &kafka.Topic{Name: "testAdapterTopic"})
+	assert.Equal(t, call.WaitForResponse, true)
+	assert.Equal(t, call.Key, parentDeviceId)
+	assert.Equal(t, call.KvArgs[0], &kafka.KVArg{Key: "device_id", Value: &voltha.ID{Id: parentDeviceId}})
+
+	assert.Equal(t, nil, error)
+	assert.Equal(t, 2, len(devices.Items))
+}
+
+func TestCoreProxy_GetChildDevices_fail_unmarhsal(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: &voltha.LogicalDevice{Id: "testDevice"},
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	parentDeviceId := "aabbcc"
+	devices, error := proxy.GetChildDevices(context.TODO(), parentDeviceId)
+
+	assert.Nil(t, devices)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+}
+
+func TestUnPackResponse_error_codes(t *testing.T) {
+
+	testCases := []struct {
+		icCode   ic.ErrorCodeCodes
+		grpcCode codes.Code
+	}{
+		{ic.ErrorCode_UNSUPPORTED_REQUEST, codes.Unavailable},
+		{ic.ErrorCode_INVALID_PARAMETERS, codes.InvalidArgument},
+		{ic.ErrorCode_DEADLINE_EXCEEDED, codes.DeadlineExceeded},
+	}
+
+	for _, tc := range testCases {
+		response, err := ptypes.MarshalAny(&ic.Error{Reason: "test-reason", Code: tc.icCode})
+		assert.Nil(t, err)
+
+		error := unPackResponse(logger, "TestRPC", "testDevice", false, response)
+
+		parsedErr, _ := status.FromError(error)
+		assert.Equal(t, parsedErr.Code(), tc.grpcCode, "wrong grpc code for ic code %v", tc.icCode)
+	}
+}
+
+func TestUnPackResponse_unparseable_error(t *testing.T) {
+
+	// a failure response that does not hold an ic.Error must not be dressed up as a real
+	// failure code; it surfaces as Internal with the rpc name and the unmarshal problem
+	malformed := &any.Any{TypeUrl: "type.googleapis.com/not.a.real.Type", Value: []byte{0xde, 0xad}}
+
+	error := unPackResponse(logger, "TestRPC", "testDevice", false, malformed)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.Internal)
+	assert.Contains(t, parsedErr.Message(), "TestRPC")
+	assert.Contains(t, parsedErr.Message(), "unparseable")
+}
+
+func TestUnPackResponse_success(t *testing.T) {
+	assert.Nil(t, unPackResponse(logger, "TestRPC", "testDevice", true, nil))
+}
+
+// blockingKafkaICProxy ignores context cancellation, emulating a stuck kafka proxy
+type blockingKafkaICProxy struct {
+	mocks.MockKafkaICProxy
+}
+
+func (b *blockingKafkaICProxy) InvokeRPC(ctx context.Context, rpc string, toTopic *kafka.Topic, replyToTopic *kafka.Topic,
+	waitForResponse bool, key string, kvArgs ...*kafka.KVArg) (bool, *any.Any) {
+	time.Sleep(5 * time.Second)
+	return false, nil
+}
+
+func TestCoreProxy_invokeRPC_returns_promptly_on_timeout(t *testing.T) {
+
+	proxy := NewCoreProxy(&blockingKafkaICProxy{}, "testAdapterTopic", "testCoreTopic",
+		WithDefaultTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	error := proxy.DeviceUpdate(context.Background(), &voltha.Device{Id: "testDevice"})
+	elapsed := time.Since(start)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.DeadlineExceeded)
+	// the call must honor the 50ms deadline instead of waiting out the blocked proxy
+	assert.True(t, elapsed < 2*time.Second, "call took %v despite a 50ms deadline", elapsed)
+}
+
+func TestCoreProxy_DeviceUpdate_nil_device(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	error := proxy.DeviceUpdate(context.TODO(), nil)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+	// the request must be rejected before anything reaches the kafka proxy
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestCoreProxy_DeviceUpdate_empty_id(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	error := proxy.DeviceUpdate(context.TODO(), &voltha.Device{})
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestCoreProxy_DeviceUpdate_cancelled_context(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	error := proxy.DeviceUpdate(ctx, &voltha.Device{Id: "testDevice"})
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.Canceled)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestCoreProxy_DeviceUpdate_expired_context(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	error := proxy.DeviceUpdate(ctx, &voltha.Device{Id: "testDevice"})
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.DeadlineExceeded)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestCoreProxy_DevicePMConfigUpdate_empty_id(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	error := proxy.DevicePMConfigUpdate(context.TODO(), &voltha.PmConfigs{})
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestIsTransientRPCFailure(t *testing.T) {
+
+	deadline, err := ptypes.MarshalAny(&ic.Error{Reason: "timeout", Code: ic.ErrorCode_DEADLINE_EXCEEDED})
+	assert.Nil(t, err)
+	assert.True(t, isTransientRPCFailure(deadline))
+
+	invalid, err := ptypes.MarshalAny(&ic.Error{Reason: "bad-args", Code: ic.ErrorCode_INVALID_PARAMETERS})
+	assert.Nil(t, err)
+	assert.False(t, isTransientRPCFailure(invalid))
+
+	// the zero-value code produced by reply encoders that never set Code; definitive
+	unsupported, err := ptypes.MarshalAny(&ic.Error{Reason: "device-not-found"})
+	assert.Nil(t, err)
+	assert.False(t, isTransientRPCFailure(unsupported))
+
+	malformed := &any.Any{TypeUrl: "type.googleapis.com/not.a.real.Type", Value: []byte{0xde, 0xad}}
+	assert.True(t, isTransientRPCFailure(malformed))
+}
+
+func TestCoreProxy_invokeReadRPC_retries_transient(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+	mockKafkaIcProxy.SetRpcResponse("GetDevice", false, &ic.Error{Reason: "timeout", Code: ic.ErrorCode_DEADLINE_EXCEEDED})
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic",
+		WithReadRetries(2, time.Millisecond))
+
+	device, error := proxy.GetDevice(context.TODO(), "aabbcc", "aabbcc")
+
+	assert.Nil(t, device)
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.DeadlineExceeded)
+	// the first attempt plus both configured retries
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 3)
+}
+
+func TestCoreProxy_invokeReadRPC_no_retry_on_definitive_error(t *testing.T) {
+
+	testCases := []struct {
+		name  string
+		icErr *ic.Error
+		code  codes.Code
+	}{
+		{"invalid parameters", &ic.Error{Reason: "bad-args", Code: ic.ErrorCode_INVALID_PARAMETERS}, codes.InvalidArgument},
+		{"unset code from a plain handler error", &ic.Error{Reason: "device-not-found"}, codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+			InvokeRpcSpy: mocks.InvokeRpcSpy{
+				Calls: make(map[int]mocks.InvokeRpcArgs),
+			},
+		}
+		mockKafkaIcProxy.SetRpcResponse("GetDevice", false, tc.icErr)
+
+		proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic",
+			WithReadRetries(3, time.Millisecond))
+
+		device, error := proxy.GetDevice(context.TODO(), "aabbcc", "aabbcc")
+
+		assert.Nil(t, device, tc.name)
+		parsedErr, _ := status.FromError(error)
+		assert.Equal(t, parsedErr.Code(), tc.code, tc.name)
+		// a definitive error must not burn the configured retries
+		assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1, tc.name)
+	}
+}
+
+func TestCoreProxy_invokeReadRPC_backoff_honors_context(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+	mockKafkaIcProxy.SetRpcResponse("GetDevice", false, &ic.Error{Reason: "timeout", Code: ic.ErrorCode_DEADLINE_EXCEEDED})
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic",
+		WithReadRetries(5, 10*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	device, error := proxy.GetDevice(ctx, "aabbcc", "aabbcc")
+	elapsed := time.Since(start)
+
+	assert.Nil(t, device)
+	assert.NotNil(t, error)
+	// the caller's deadline must cut the 10s backoff short
+	assert.True(t, elapsed < 2*time.Second, "retry backoff ignored the caller's context, took %v", elapsed)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1)
+}
+
+func TestCoreProxy_reply_topic_suffix_subscribes(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic",
+		WithReplyTopicSuffix("instance1"))
+
+	error := proxy.DeviceUpdate(context.TODO(), &voltha.Device{Id: "testDevice"})
+	assert.Equal(t, nil, error)
+
+	// the dedicated reply topic must be consumed before replies are directed at it
+	_, subscribed := mockKafkaIcProxy.DefaultHandlerSubscriptions["testAdapterTopic_instance1"]
+	assert.True(t, subscribed)
+	assert.Equal(t, 1, len(mockKafkaIcProxy.DefaultHandlerSubscriptions))
+
+	call := mockKafkaIcProxy.InvokeRpcSpy.Calls[1]
+	assert.Equal(t, call.ReplyToTopic, &kafka.Topic{Name: "testAdapterTopic_instance1"})
+}
+
+func TestCoreProxy_scripted_responses(t *testing.T) {
+
+	// one mock serving several different RPCs, each with its own scripted outcome
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+	mockKafkaIcProxy.SetRpcResponse("ListDeviceIds", true, &voltha.IDs{Items: []*voltha.ID{{Id: "device1"}, {Id: "device2"}}})
+	mockKafkaIcProxy.SetRpcResponse("GetDevicePort", true, &voltha.Port{PortNo: 42})
+	mockKafkaIcProxy.SetRpcResponse("GetLogicalDeviceId", true, &ic.StrType{Val: "logicalDevice1"})
+	mockKafkaIcProxy.SetRpcResponse("DeviceDeleted", false, &ic.Error{Reason: "unknown-device", Code: ic.ErrorCode_INVALID_PARAMETERS})
+	// a bare success with no payload, as returned by the error-only methods
+	mockKafkaIcProxy.SetRpcResponse("SendDeviceError", true, nil)
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	ids, error := proxy.ListDeviceIds(context.TODO())
+	assert.Equal(t, nil, error)
+	assert.Equal(t, 2, len(ids.Items))
+
+	port, error := proxy.GetDevicePort(context.TODO(), "aabbcc", 42)
+	assert.Equal(t, nil, error)
+	assert.Equal(t, uint32(42), port.PortNo)
+
+	logicalDeviceId, error := proxy.GetLogicalDeviceId(context.TODO(), "aabbcc")
+	assert.Equal(t, nil, error)
+	assert.Equal(t, "logicalDevice1", logicalDeviceId)
+
+	error = proxy.DeviceDeleted(context.TODO(), "aabbcc")
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+
+	error = proxy.SendDeviceError(context.TODO(), "aabbcc", 1, "oper-failure")
+	assert.Equal(t, nil, error)
+
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 5)
+}
+
+func TestCoreProxy_ChildDeviceDetectedWithArgs_out_of_range(t *testing.T) {
+
+	testCases := []struct {
+		name      string
+		channelId int
+		onuId     int64
+	}{
+		{"negative channel id", -1, 1},
+		{"channel id above MaxUint32", math.MaxUint32 + 1, 1},
+		{"negative onu id", 1, -1},
+		{"onu id above MaxUint32", 1, math.MaxUint32 + 1},
+	}
+
+	for _, tc := range testCases {
+		var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+			InvokeRpcSpy: mocks.InvokeRpcSpy{
+				Calls: make(map[int]mocks.InvokeRpcArgs),
+			},
+		}
+
+		proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+		device, error := proxy.ChildDeviceDetectedWithArgs(context.TODO(), "aabbcc", ChildDeviceArgs{
+			ChannelId: tc.channelId,
+			OnuId:     tc.onuId,
+		})
+
+		assert.Nil(t, device, tc.name)
+		parsedErr, _ := status.FromError(error)
+		assert.Equal(t, parsedErr.Code(), codes.InvalidArgument, tc.name)
+		assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0, tc.name)
+	}
+}
+
+func TestCoreProxy_ChildDeviceDetectedWithArgs_boundary(t *testing.T) {
+
+	// both identifiers at their largest representable on-the-wire value must be accepted
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:    make(map[int]mocks.InvokeRpcArgs),
+			Response: &voltha.Device{Id: "testDevice"},
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	device, error := proxy.ChildDeviceDetectedWithArgs(context.TODO(), "aabbcc", ChildDeviceArgs{
+		ChannelId: math.MaxUint32,
+		OnuId:     math.MaxUint32,
+	})
+
+	assert.Equal(t, nil, error)
+	assert.Equal(t, "testDevice", device.Id)
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 1)
+}
+
+func TestCoreProxy_GetChildDevicesPaged_bad_token(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls: make(map[int]mocks.InvokeRpcArgs),
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	for _, token := range []string{"not-a-number", "-5"} {
+		devices, nextPageToken, error := proxy.GetChildDevicesPaged(context.TODO(), "aabbcc", 10, token)
+
+		assert.Nil(t, devices)
+		assert.Equal(t, "", nextPageToken)
+		parsedErr, _ := status.FromError(error)
+		assert.Equal(t, parsedErr.Code(), codes.InvalidArgument)
+	}
+	assert.Equal(t, mockKafkaIcProxy.InvokeRpcSpy.CallCount, 0)
+}
+
+func TestCoreProxy_GetChildDevices_fail_timeout(t *testing.T) {
+
+	var mockKafkaIcProxy = mocks.MockKafkaICProxy{
+		InvokeRpcSpy: mocks.InvokeRpcSpy{
+			Calls:   make(map[int]mocks.InvokeRpcArgs),
+			Timeout: true,
+		},
+	}
+
+	proxy := NewCoreProxy(&mockKafkaIcProxy, "testAdapterTopic", "testCoreTopic")
+
+	parentDeviceId := "aabbcc"
+	devices, error := proxy.GetChildDevices(context.TODO(), parentDeviceId)
+
+	assert.Nil(t, devices)
+
+	parsedErr, _ := status.FromError(error)
+	assert.Equal(t, parsedErr.Code(), codes.DeadlineExceeded)
+}
@@ -62,6 +62,10 @@ const (
 	defaultNotLiveProbeInterval      = 5 * time.Second // Probe more frequently when not alive
 	defaultProbeHost                 = ""
 	defaultProbePort                 = 8080
+	defaultKafkaProducerMaxRetries   = 6
+	defaultKafkaNumPartitions        = 3
+	defaultKafkaFlushFrequency       = 5
+	defaultKafkaRetryBackoff         = 30 * time.Millisecond
 )
 
 // RWCoreFlags represents the set of configurations used by the read-write core service
@@ -100,6 +104,10 @@ type RWCoreFlags struct {
 	NotLiveProbeInterval      time.Duration
 	ProbeHost                 string
 	ProbePort                 int
+	KafkaProducerMaxRetries   int
+	KafkaNumPartitions        int
+	KafkaFlushFrequency       int
+	KafkaRetryBackoff         time.Duration
 }
 
 func init() {
@@ -145,6 +153,10 @@ func NewRWCoreFlags() *RWCoreFlags {
 		NotLiveProbeInterval:      defaultNotLiveProbeInterval,
 		ProbeHost:                 defaultProbeHost,
 		ProbePort:                 defaultProbePort,
+		KafkaProducerMaxRetries:   defaultKafkaProducerMaxRetries,
+		KafkaNumPartitions:        defaultKafkaNumPartitions,
+		KafkaFlushFrequency:       defaultKafkaFlushFrequency,
+		KafkaRetryBackoff:         defaultKafkaRetryBackoff,
 	}
 	return &rwCoreFlag
 }
@@ -242,5 +254,17 @@ func (cf *RWCoreFlags) ParseCommandArguments() {
 	help = fmt.Sprintf("The port on which to listen to answer liveness and readiness probe queries over HTTP.")
 	flag.IntVar(&(cf.ProbePort), "probe_port", defaultProbePort, help)
 
+	help = fmt.Sprintf("The number of times the kafka producer retries sending a message")
+	flag.IntVar(&(cf.KafkaProducerMaxRetries), "kafka_producer_max_retries", defaultKafkaProducerMaxRetries, help)
+
+	help = fmt.Sprintf("The number of partitions used for new kafka topics")
+	flag.IntVar(&(cf.KafkaNumPartitions), "kafka_num_partitions", defaultKafkaNumPartitions, help)
+
+	help = fmt.Sprintf("The kafka producer flush frequency in milliseconds")
+	flag.IntVar(&(cf.KafkaFlushFrequency), "kafka_flush_frequency", defaultKafkaFlushFrequency, help)
+
+	help = fmt.Sprintf("The time to wait between kafka producer send retries")
+	flag.DurationVar(&(cf.KafkaRetryBackoff), "kafka_retry_backoff", defaultKafkaRetryBackoff, help)
+
 	flag.Parse()
 }
@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -68,28 +70,44 @@ func newKVClient(storeType string, address string, timeout int) (kvstore.Client,
 	return nil, errors.New("unsupported-kv-store")
 }
 
-func newKafkaClient(clientType string, host string, port int, instanceID string, livenessChannelInterval time.Duration) (kafka.Client, error) {
+// kafkaClientConstructors maps a kafka client type name to its constructor; an additional
+// client implementation plugs in by adding an entry
+var kafkaClientConstructors = map[string]func(cf *config.RWCoreFlags, instanceID string) kafka.Client{
+	"sarama": newSaramaClient,
+}
+
+func newSaramaClient(cf *config.RWCoreFlags, instanceID string) kafka.Client {
+	return kafka.NewSaramaClient(
+		kafka.Host(cf.KafkaAdapterHost),
+		kafka.Port(cf.KafkaAdapterPort),
+		kafka.ConsumerType(kafka.GroupCustomer),
+		kafka.ProducerReturnOnErrors(true),
+		kafka.ProducerReturnOnSuccess(true),
+		kafka.ProducerMaxRetries(cf.KafkaProducerMaxRetries),
+		kafka.NumPartitions(cf.KafkaNumPartitions),
+		kafka.ConsumerGroupName(instanceID),
+		kafka.ConsumerGroupPrefix(instanceID),
+		kafka.AutoCreateTopic(true),
+		kafka.ProducerFlushFrequency(cf.KafkaFlushFrequency),
+		kafka.ProducerRetryBackoff(cf.KafkaRetryBackoff),
+		kafka.LivenessChannelInterval(cf.LiveProbeInterval/2),
+	)
+}
+
+func newKafkaClient(clientType string, cf *config.RWCoreFlags, instanceID string) (kafka.Client, error) {
 
 	log.Infow("kafka-client-type", log.Fields{"client": clientType})
-	switch clientType {
-	case "sarama":
-		return kafka.NewSaramaClient(
-			kafka.Host(host),
-			kafka.Port(port),
-			kafka.ConsumerType(kafka.GroupCustomer),
-			kafka.ProducerReturnOnErrors(true),
-			kafka.ProducerReturnOnSuccess(true),
-			kafka.ProducerMaxRetries(6),
-			kafka.NumPartitions(3),
-			kafka.ConsumerGroupName(instanceID),
-			kafka.ConsumerGroupPrefix(instanceID),
-			kafka.AutoCreateTopic(true),
-			kafka.ProducerFlushFrequency(5),
-			kafka.ProducerRetryBackoff(time.Millisecond*30),
-			kafka.LivenessChannelInterval(livenessChannelInterval),
-		), nil
+	constructor, exist := kafkaClientConstructors[clientType]
+	if !exist {
+		supported := make([]string, 0, len(kafkaClientConstructors))
+		for name := range kafkaClientConstructors {
+			supported = append(supported, name)
+		}
+		sort.Strings(supported)
+		return nil, fmt.Errorf("unsupported-kafka-client-type: %s, supported types: %s",
+			clientType, strings.Join(supported, ", "))
 	}
-	return nil, errors.New("unsupported-client-type")
+	return constructor(cf, instanceID), nil
 }
 
 func newRWCore(cf *config.RWCoreFlags) *rwCore {
@@ -123,12 +141,9 @@ func (rw *rwCore) start(ctx context.Context, instanceID string) {
 	}
 
 	// Setup Kafka Client
-	if rw.kafkaClient, err = newKafkaClient("sarama",
-		rw.config.KafkaAdapterHost,
-		rw.config.KafkaAdapterPort,
-		instanceID,
-		rw.config.LiveProbeInterval/2); err != nil {
-		log.Fatal("Unsupported-kafka-client")
+	// Fail fast on an unsupported client type, naming the supported ones
+	if rw.kafkaClient, err = newKafkaClient("sarama", rw.config, instanceID); err != nil {
+		log.Fatal(err)
 	}
 
 	// Create the core service